@@ -1,13 +1,64 @@
 package main
 
 import (
+	"bufio"
+	"cmp"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"iter"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type StreamX[T any] iter.Seq[T]
 type StreamXMapper[Input any, Output any] func(input StreamX[Input]) StreamX[Output]
 
+// Pair holds two values produced together, e.g. by Zip.
+type Pair[A any, B any] struct {
+	First  A
+	Second B
+}
+
+// Entry holds a single key-value pair of a map, e.g. from MapEntries.
+type Entry[K any, V any] struct {
+	Key   K
+	Value V
+}
+
+// Indexed holds an item together with its position in a stream, e.g. from Enumerate.
+type Indexed[T any] struct {
+	Index int
+	Value T
+}
+
+// Group holds consecutive items sharing the same key, e.g. from GroupAdjacent.
+type Group[K any, T any] struct {
+	Key   K
+	Items []T
+}
+
+// Number is the set of types supported by numeric terminals like Sum.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Result carries either a value or the error produced while computing it.
+type Result[T any] struct {
+	Value T
+	Err   error
+}
+
 // SliceToStream converts a slice into a StreamX iterator.
 func SliceToStream[T any](inputSlice []T) StreamX[T] {
 	return func(yield func(T) bool) {
@@ -20,11 +71,18 @@ func SliceToStream[T any](inputSlice []T) StreamX[T] {
 }
 
 func StreamToSlice[Type any](stream StreamX[Type]) []Type {
-	result := make([]Type, 0)
-	for item := range stream {
-		result = append(result, item)
+	return CollectN[Type](0)(stream)
+}
+
+// CollectN collects the stream into a slice preallocated for sizeHint items.
+func CollectN[Type any](sizeHint int) func(stream StreamX[Type]) []Type {
+	return func(stream StreamX[Type]) []Type {
+		result := make([]Type, 0, max(sizeHint, 0))
+		for item := range stream {
+			result = append(result, item)
+		}
+		return result
 	}
-	return result
 }
 
 func Map[Input, Output any](mapper func(Input) Output) StreamXMapper[Input, Output] {
@@ -95,6 +153,7 @@ func Batch[Input any](size int) StreamXMapper[Input, []Input] {
 	}
 }
 
+// Flat yields every element of each input slice in order; nil and empty slices contribute nothing.
 func Flat[Output any]() StreamXMapper[[]Output, Output] {
 	return func(inputStream StreamX[[]Output]) StreamX[Output] {
 		return func(yield func(Output) bool) {
@@ -120,6 +179,7 @@ func Run[Type any](stream StreamX[Type]) bool {
 	return true
 }
 
+// Pipeline applies mappers in order; with no mappers it is the identity and returns the input stream unchanged.
 func Pipeline[Input any](mappers ...StreamXMapper[Input, Input]) StreamXMapper[Input, Input] {
 	return func(inputStream StreamX[Input]) StreamX[Input] {
 		for _, mapper := range mappers {
@@ -138,6 +198,2338 @@ func Pipe[Input any, Output any](input StreamX[Input], mapper StreamXMapper[Inpu
 	return output
 }
 
+// Reduce folds the stream into a single accumulated value.
+// An empty stream returns the initial value unchanged.
+func Reduce[Type any, Acc any](initial Acc, reducer func(Acc, Type) Acc) func(stream StreamX[Type]) Acc {
+	return func(stream StreamX[Type]) Acc {
+		acc := initial
+		for item := range stream {
+			acc = reducer(acc, item)
+		}
+		return acc
+	}
+}
+
+// FlatMap maps each item to a sub-stream and emits all of its items
+// before moving to the next input.
+func FlatMap[Input, Output any](mapper func(Input) StreamX[Output]) StreamXMapper[Input, Output] {
+	return func(inputStream StreamX[Input]) StreamX[Output] {
+		return func(yield func(Output) bool) {
+			inputStream(func(val Input) bool {
+				for item := range mapper(val) {
+					if !yield(item) {
+						return false // Stop pulling the next input
+					}
+				}
+				return true
+			})
+		}
+	}
+}
+
+// Take yields at most the first n items and then stops consuming the input.
+func Take[Type any](n int) StreamXMapper[Type, Type] {
+	return func(inputStream StreamX[Type]) StreamX[Type] {
+		return func(yield func(Type) bool) {
+			if n <= 0 {
+				return // Nothing to emit, do not start the input
+			}
+
+			taken := 0
+			inputStream(func(val Type) bool {
+				taken++
+				if !yield(val) {
+					return false
+				}
+				return taken < n // Stop the input once n items are emitted
+			})
+		}
+	}
+}
+
+// Drop skips the first n items and passes through everything after.
+func Drop[Type any](n int) StreamXMapper[Type, Type] {
+	return func(inputStream StreamX[Type]) StreamX[Type] {
+		return func(yield func(Type) bool) {
+			dropped := 0
+			inputStream(func(val Type) bool {
+				if dropped < n {
+					dropped++
+					return true // Skip the leading items
+				}
+				return yield(val)
+			})
+		}
+	}
+}
+
+// TakeWhile yields items while the condition holds and stops the input at the first failure.
+func TakeWhile[Type any](condition func(Type) bool) StreamXMapper[Type, Type] {
+	return func(inputStream StreamX[Type]) StreamX[Type] {
+		return func(yield func(Type) bool) {
+			inputStream(func(val Type) bool {
+				if !condition(val) {
+					return false // Stop the input
+				}
+				return yield(val)
+			})
+		}
+	}
+}
+
+// DropWhile skips leading items while the condition holds and then passes the rest through.
+// Once the condition fails it is not checked again.
+func DropWhile[Type any](condition func(Type) bool) StreamXMapper[Type, Type] {
+	return func(inputStream StreamX[Type]) StreamX[Type] {
+		return func(yield func(Type) bool) {
+			dropping := true
+			inputStream(func(val Type) bool {
+				if dropping {
+					if condition(val) {
+						return true // Skip the leading items
+					}
+					dropping = false
+				}
+				return yield(val)
+			})
+		}
+	}
+}
+
+// Distinct yields each value only the first time it is seen, preserving order.
+// Seen values are kept in memory, so it grows unbounded on unbounded streams.
+func Distinct[Type comparable]() StreamXMapper[Type, Type] {
+	return DistinctBy(func(val Type) Type {
+		return val
+	})
+}
+
+// DistinctBy yields an item only the first time its key is seen, keeping the first item per key.
+// Seen keys are kept in memory, so it grows unbounded on unbounded streams.
+func DistinctBy[Type any, Key comparable](key func(Type) Key) StreamXMapper[Type, Type] {
+	return func(inputStream StreamX[Type]) StreamX[Type] {
+		return func(yield func(Type) bool) {
+			seen := make(map[Key]struct{})
+			inputStream(func(val Type) bool {
+				k := key(val)
+				if _, ok := seen[k]; ok {
+					return true // Skip duplicates
+				}
+				seen[k] = struct{}{}
+				return yield(val)
+			})
+		}
+	}
+}
+
+// Scan yields the accumulator after each input is folded in.
+func Scan[Input, Acc any](initial Acc, reducer func(Acc, Input) Acc) StreamXMapper[Input, Acc] {
+	return func(inputStream StreamX[Input]) StreamX[Acc] {
+		return func(yield func(Acc) bool) {
+			acc := initial
+			inputStream(func(val Input) bool {
+				acc = reducer(acc, val)
+				return yield(acc)
+			})
+		}
+	}
+}
+
+// Zip combines two streams pairwise and stops when either stream is exhausted.
+func Zip[A, B any](a StreamX[A], b StreamX[B]) StreamX[Pair[A, B]] {
+	return func(yield func(Pair[A, B]) bool) {
+		// Pull b in lockstep with a
+		nextB, stop := iter.Pull(iter.Seq[B](b))
+		defer stop()
+
+		a(func(valA A) bool {
+			valB, ok := nextB()
+			if !ok {
+				return false // b is exhausted
+			}
+			return yield(Pair[A, B]{First: valA, Second: valB})
+		})
+	}
+}
+
+// Concat yields all items of each stream in turn.
+func Concat[Type any](streams ...StreamX[Type]) StreamX[Type] {
+	return func(yield func(Type) bool) {
+		for _, stream := range streams {
+			for item := range stream {
+				if !yield(item) {
+					return // Do not start the next stream
+				}
+			}
+		}
+	}
+}
+
+// Merge runs each stream in its own goroutine and yields items as they arrive.
+// The order of items across streams is nondeterministic.
+func Merge[Type any](streams ...StreamX[Type]) StreamX[Type] {
+	return func(yield func(Type) bool) {
+		items := make(chan Type)
+		done := make(chan struct{})
+		var wg sync.WaitGroup
+
+		for _, stream := range streams {
+			wg.Add(1)
+			go func(stream StreamX[Type]) {
+				defer wg.Done()
+				stream(func(val Type) bool {
+					select {
+					case items <- val:
+						return true
+					case <-done:
+						return false // Consumer stopped
+					}
+				})
+			}(stream)
+		}
+
+		// Close the output once every source is drained
+		go func() {
+			wg.Wait()
+			close(items)
+		}()
+
+		defer func() {
+			close(done)
+			wg.Wait()
+		}()
+
+		for item := range items {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// MapParallel applies the mapper to up to concurrency items at once and yields results in input order.
+// A concurrency of 1 or less behaves like Map.
+func MapParallel[Input, Output any](concurrency int, mapper func(Input) Output) StreamXMapper[Input, Output] {
+	if concurrency <= 1 {
+		return Map(mapper)
+	}
+
+	return func(inputStream StreamX[Input]) StreamX[Output] {
+		return func(yield func(Output) bool) {
+			// Each item gets its own result slot, queued in input order
+			pending := make(chan chan Output, concurrency)
+			slots := make(chan struct{}, concurrency)
+			done := make(chan struct{})
+			var wg sync.WaitGroup
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer close(pending)
+
+				inputStream(func(val Input) bool {
+					select {
+					case slots <- struct{}{}:
+					case <-done:
+						return false // Consumer stopped
+					}
+
+					result := make(chan Output, 1)
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						result <- mapper(val)
+						<-slots
+					}()
+
+					select {
+					case pending <- result:
+						return true
+					case <-done:
+						return false // Consumer stopped
+					}
+				})
+			}()
+
+			defer func() {
+				close(done)
+				wg.Wait()
+			}()
+
+			for result := range pending {
+				if !yield(<-result) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// MapParallelUnordered applies the mapper to up to concurrency items at once and yields results as they complete.
+// A concurrency of 1 or less behaves like Map.
+func MapParallelUnordered[Input, Output any](concurrency int, mapper func(Input) Output) StreamXMapper[Input, Output] {
+	if concurrency <= 1 {
+		return Map(mapper)
+	}
+
+	return func(inputStream StreamX[Input]) StreamX[Output] {
+		return func(yield func(Output) bool) {
+			results := make(chan Output)
+			slots := make(chan struct{}, concurrency)
+			done := make(chan struct{})
+			var wg sync.WaitGroup
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				inputStream(func(val Input) bool {
+					select {
+					case slots <- struct{}{}:
+					case <-done:
+						return false // Consumer stopped
+					}
+
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						defer func() { <-slots }()
+
+						select {
+						case results <- mapper(val):
+						case <-done:
+						}
+					}()
+					return true
+				})
+			}()
+
+			// Close the output once the input and all workers are finished
+			go func() {
+				wg.Wait()
+				close(results)
+			}()
+
+			defer func() {
+				close(done)
+				wg.Wait()
+			}()
+
+			for result := range results {
+				if !yield(result) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// WithContext passes items through and stops the stream once the context is done.
+func WithContext[Type any](ctx context.Context) StreamXMapper[Type, Type] {
+	return func(inputStream StreamX[Type]) StreamX[Type] {
+		return func(yield func(Type) bool) {
+			inputStream(func(val Type) bool {
+				// Check the context before yielding each item
+				if ctx.Err() != nil {
+					return false
+				}
+				return yield(val)
+			})
+		}
+	}
+}
+
+// MapResult applies a fallible mapper and wraps each outcome into a Result.
+func MapResult[Input, Output any](mapper func(Input) (Output, error)) StreamXMapper[Input, Result[Output]] {
+	return Map(func(val Input) Result[Output] {
+		output, err := mapper(val)
+		return Result[Output]{Value: output, Err: err}
+	})
+}
+
+// FilterErrors drops failed results and yields the values of successful ones.
+func FilterErrors[Type any]() StreamXMapper[Result[Type], Type] {
+	return func(inputStream StreamX[Result[Type]]) StreamX[Type] {
+		return func(yield func(Type) bool) {
+			inputStream(func(val Result[Type]) bool {
+				if val.Err != nil {
+					return true // Skip errors
+				}
+				return yield(val.Value)
+			})
+		}
+	}
+}
+
+// CollectErrors consumes the stream and returns the first error encountered, or nil.
+func CollectErrors[Type any](stream StreamX[Result[Type]]) error {
+	for item := range stream {
+		if item.Err != nil {
+			return item.Err
+		}
+	}
+	return nil
+}
+
+// ForEach calls fn for each item and stops the stream once fn returns false.
+func ForEach[Type any](fn func(Type) bool) func(stream StreamX[Type]) {
+	return func(stream StreamX[Type]) {
+		stream(fn)
+	}
+}
+
+// Count consumes the whole stream and returns the number of items.
+func Count[Type any](stream StreamX[Type]) int {
+	count := 0
+	for range stream {
+		count++
+	}
+	return count
+}
+
+// CountWhere consumes the whole stream and returns the number of items satisfying the condition.
+func CountWhere[Type any](condition func(Type) bool) func(stream StreamX[Type]) int {
+	return func(stream StreamX[Type]) int {
+		return Count(Filter(condition)(stream))
+	}
+}
+
+// Find returns the first item satisfying the condition and stops the stream immediately.
+// It returns the zero value and false if nothing matches.
+func Find[Type any](condition func(Type) bool) func(stream StreamX[Type]) (Type, bool) {
+	return func(stream StreamX[Type]) (Type, bool) {
+		for item := range stream {
+			if condition(item) {
+				return item, true
+			}
+		}
+		var zero Type
+		return zero, false
+	}
+}
+
+// Any reports whether some item satisfies the condition, stopping at the first match.
+func Any[Type any](condition func(Type) bool) func(stream StreamX[Type]) bool {
+	return func(stream StreamX[Type]) bool {
+		_, found := Find(condition)(stream)
+		return found
+	}
+}
+
+// All reports whether every item satisfies the condition, stopping at the first failure.
+// An empty stream returns true.
+func All[Type any](condition func(Type) bool) func(stream StreamX[Type]) bool {
+	return func(stream StreamX[Type]) bool {
+		return !Any(func(val Type) bool {
+			return !condition(val)
+		})(stream)
+	}
+}
+
+// None reports whether no item satisfies the condition, stopping at the first match.
+// An empty stream returns true.
+func None[Type any](condition func(Type) bool) func(stream StreamX[Type]) bool {
+	return func(stream StreamX[Type]) bool {
+		return !Any(condition)(stream)
+	}
+}
+
+// Window yields sliding windows of size items, advancing by step items each time.
+// Only full windows are emitted. When step exceeds size, the items in between are skipped.
+func Window[Type any](size, step int) StreamXMapper[Type, []Type] {
+	return WindowPartial[Type](size, step, false)
+}
+
+// WindowPartial is like Window, but when emitPartialTail is true the trailing incomplete window is
+// emitted at the end of the stream. The tail is only emitted if it holds items not covered by an
+// earlier window, so with step < size the overlap left over after the last full window is not repeated.
+func WindowPartial[Type any](size, step int, emitPartialTail bool) StreamXMapper[Type, []Type] {
+	if size <= 0 || step <= 0 {
+		panic("streamx: Window size and step must be positive")
+	}
+
+	return func(inputStream StreamX[Type]) StreamX[[]Type] {
+		return func(yield func([]Type) bool) {
+			var window []Type
+			skip := 0
+			fresh := 0 // Items in the window not yet emitted
+			stopped := false
+			inputStream(func(val Type) bool {
+				if skip > 0 {
+					skip--
+					return true // Skip items between windows
+				}
+
+				window = append(window, val)
+				fresh++
+				if len(window) < size {
+					return true // Continue iterating
+				}
+
+				toEmit := window
+				fresh = 0
+				if step < size {
+					// Keep the overlapping tail for the next window
+					window = append([]Type(nil), window[step:]...)
+				} else {
+					window = nil
+					skip = step - size
+				}
+				stopped = !yield(toEmit)
+				return !stopped
+			})
+
+			// If there is an incomplete window with new items, yield it
+			if emitPartialTail && !stopped && fresh > 0 {
+				yield(window)
+			}
+		}
+	}
+}
+
+// GroupBy consumes the whole stream and buckets items by key, preserving order within each bucket.
+func GroupBy[Type any, Key comparable](key func(Type) Key) func(stream StreamX[Type]) map[Key][]Type {
+	return func(stream StreamX[Type]) map[Key][]Type {
+		groups := make(map[Key][]Type)
+		for item := range stream {
+			k := key(item)
+			groups[k] = append(groups[k], item)
+		}
+		return groups
+	}
+}
+
+// Partition consumes the whole stream and splits items by the condition, preserving order.
+func Partition[Type any](condition func(Type) bool) func(stream StreamX[Type]) (matched []Type, rest []Type) {
+	return func(stream StreamX[Type]) ([]Type, []Type) {
+		matched := make([]Type, 0)
+		rest := make([]Type, 0)
+		for item := range stream {
+			if condition(item) {
+				matched = append(matched, item)
+			} else {
+				rest = append(rest, item)
+			}
+		}
+		return matched, rest
+	}
+}
+
+// Reverse yields the items in reverse order.
+// It buffers the entire input stream in memory before emitting anything.
+func Reverse[Type any]() StreamXMapper[Type, Type] {
+	return func(inputStream StreamX[Type]) StreamX[Type] {
+		return func(yield func(Type) bool) {
+			buffered := StreamToSlice(inputStream)
+			for i := len(buffered) - 1; i >= 0; i-- {
+				if !yield(buffered[i]) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Sort yields the items in stable sorted order according to less.
+// It buffers the entire input stream in memory before emitting anything.
+func Sort[Type any](less func(a, b Type) bool) StreamXMapper[Type, Type] {
+	return func(inputStream StreamX[Type]) StreamX[Type] {
+		return func(yield func(Type) bool) {
+			buffered := StreamToSlice(inputStream)
+			sort.SliceStable(buffered, func(i, j int) bool {
+				return less(buffered[i], buffered[j])
+			})
+			SliceToStream(buffered)(yield)
+		}
+	}
+}
+
+// SortBy yields the items in stable ascending order of their key.
+// It buffers the entire input stream in memory before emitting anything.
+func SortBy[Type any, Key cmp.Ordered](key func(Type) Key) StreamXMapper[Type, Type] {
+	return Sort(func(a, b Type) bool {
+		return key(a) < key(b)
+	})
+}
+
+// Range yields start, start+step, ... up to but not including end.
+// A negative step counts down. A zero step panics.
+func Range(start, end, step int) StreamX[int] {
+	if step == 0 {
+		panic("streamx: Range step must not be zero")
+	}
+
+	return func(yield func(int) bool) {
+		for i := start; (step > 0 && i < end) || (step < 0 && i > end); i += step {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+// Iterate produces an infinite stream of seed, next(seed), next(next(seed)), ...
+func Iterate[Type any](seed Type, next func(Type) Type) StreamX[Type] {
+	return func(yield func(Type) bool) {
+		for val := seed; yield(val); val = next(val) {
+		}
+	}
+}
+
+// Repeat yields value n times. A negative n repeats forever.
+func Repeat[Type any](value Type, n int) StreamX[Type] {
+	return func(yield func(Type) bool) {
+		for i := 0; n < 0 || i < n; i++ {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// Cycle loops over the items forever. An empty slice yields nothing.
+func Cycle[Type any](items []Type) StreamX[Type] {
+	return func(yield func(Type) bool) {
+		if len(items) == 0 {
+			return // Nothing to cycle over
+		}
+
+		for {
+			for _, item := range items {
+				if !yield(item) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Empty returns a stream that yields nothing.
+func Empty[Type any]() StreamX[Type] {
+	return func(yield func(Type) bool) {}
+}
+
+// Of returns a stream over the given values.
+func Of[Type any](items ...Type) StreamX[Type] {
+	return SliceToStream(items)
+}
+
+// ChannelToStream yields items received from the channel until it is closed.
+func ChannelToStream[Type any](ch <-chan Type) StreamX[Type] {
+	return func(yield func(Type) bool) {
+		for item := range ch {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// StreamToChannel runs the stream in a goroutine and sends its items to the returned channel.
+// The channel is closed once the stream ends. A receiver that may stop early should use
+// StreamToChannelContext and cancel it, otherwise the goroutine blocks on the next send.
+func StreamToChannel[Type any](stream StreamX[Type], buffer int) <-chan Type {
+	return StreamToChannelContext(context.Background(), stream, buffer)
+}
+
+// StreamToChannelContext is like StreamToChannel, but cancelling ctx stops the stream early:
+// the goroutine exits and the channel is closed once any buffered items have been sent.
+func StreamToChannelContext[Type any](ctx context.Context, stream StreamX[Type], buffer int) <-chan Type {
+	finished := make(chan struct{})
+	items, stop := pump(func(yield func(Type) bool) {
+		defer close(finished)
+		stream(yield)
+	}, buffer)
+
+	if ctx.Done() != nil {
+		go func() {
+			select {
+			case <-ctx.Done():
+				stop()
+			case <-finished:
+				// Stream ended on its own
+			}
+		}()
+	}
+	return items
+}
+
+// LinesFromReader yields each line of r without the trailing newline.
+// Read errors end the stream silently; use LinesFromReaderResult to observe them.
+func LinesFromReader(r io.Reader) StreamX[string] {
+	return FilterErrors[string]()(LinesFromReaderResult(r))
+}
+
+// LinesFromReaderResult yields each line of r as a Result.
+// If reading fails, the error is yielded as the last item.
+func LinesFromReaderResult(r io.Reader) StreamX[Result[string]] {
+	return func(yield func(Result[string]) bool) {
+		scanner := bufio.NewScanner(r)
+		// Let the buffer grow for arbitrarily long lines
+		scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), math.MaxInt)
+
+		for scanner.Scan() {
+			if !yield(Result[string]{Value: scanner.Text()}) {
+				return
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			yield(Result[string]{Err: err})
+		}
+	}
+}
+
+// Throttle passes every item through, waiting so at least minInterval elapses between emitted items.
+// Each wait lasts at most minInterval.
+func Throttle[Type any](minInterval time.Duration) StreamXMapper[Type, Type] {
+	return ThrottleContext[Type](context.Background(), minInterval)
+}
+
+// ThrottleContext is like Throttle but stops the stream if ctx is done, including while waiting.
+func ThrottleContext[Type any](ctx context.Context, minInterval time.Duration) StreamXMapper[Type, Type] {
+	return func(inputStream StreamX[Type]) StreamX[Type] {
+		return func(yield func(Type) bool) {
+			var last time.Time
+			inputStream(func(val Type) bool {
+				if wait := minInterval - time.Since(last); !last.IsZero() && wait > 0 {
+					timer := time.NewTimer(wait)
+					select {
+					case <-timer.C:
+					case <-ctx.Done():
+						timer.Stop()
+						return false
+					}
+				}
+				if ctx.Err() != nil {
+					return false
+				}
+
+				last = time.Now()
+				return yield(val)
+			})
+		}
+	}
+}
+
+// pump runs the stream in a goroutine and sends its items to the returned channel,
+// which is closed once the stream ends. Calling stop halts the goroutine and waits for it to exit.
+func pump[Type any](stream StreamX[Type], capacity int) (items <-chan Type, stop func()) {
+	ch := make(chan Type, capacity)
+	done := make(chan struct{})
+	exited := make(chan struct{})
+
+	go func() {
+		defer close(exited)
+		defer close(ch)
+		stream(func(val Type) bool {
+			select {
+			case ch <- val:
+				return true
+			case <-done:
+				return false // Consumer stopped
+			}
+		})
+	}()
+
+	return ch, func() {
+		close(done)
+		<-exited
+	}
+}
+
+// Debounce emits only the last item of each burst, once no new item has arrived for the quiet duration.
+// The pending item is always flushed when the stream ends.
+func Debounce[Type any](quiet time.Duration) StreamXMapper[Type, Type] {
+	return func(inputStream StreamX[Type]) StreamX[Type] {
+		return func(yield func(Type) bool) {
+			items, stop := pump(inputStream, 0)
+			defer stop()
+
+			timer := time.NewTimer(quiet)
+			timer.Stop()
+			defer timer.Stop()
+
+			var latest Type
+			pending := false
+			for {
+				select {
+				case val, ok := <-items:
+					if !ok {
+						if pending {
+							yield(latest) // Flush the final item
+						}
+						return
+					}
+					latest, pending = val, true
+					timer.Reset(quiet)
+				case <-timer.C:
+					if pending {
+						pending = false
+						if !yield(latest) {
+							return
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// BatchTimeout yields a batch once it reaches size items or timeout has elapsed since its first item,
+// whichever comes first. Empty batches are never emitted.
+func BatchTimeout[Type any](size int, timeout time.Duration) StreamXMapper[Type, []Type] {
+	return func(inputStream StreamX[Type]) StreamX[[]Type] {
+		return func(yield func([]Type) bool) {
+			items, stop := pump(inputStream, 0)
+			defer stop()
+
+			timer := time.NewTimer(timeout)
+			timer.Stop()
+			defer timer.Stop()
+
+			var batched []Type
+			flush := func() bool {
+				timer.Stop()
+				toEmit := batched
+				batched = nil // Reset the batch
+				return yield(toEmit)
+			}
+
+			for {
+				select {
+				case val, ok := <-items:
+					if !ok {
+						// If there are remaining items in the batch, yield them
+						if len(batched) > 0 {
+							flush()
+						}
+						return
+					}
+
+					batched = append(batched, val)
+					if len(batched) == 1 {
+						timer.Reset(timeout) // The first item starts the timeout
+					}
+					if len(batched) >= size && !flush() {
+						return
+					}
+				case <-timer.C:
+					if len(batched) > 0 && !flush() {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+// Buffer runs the input stream in a goroutine ahead of the consumer, holding up to capacity items.
+func Buffer[Type any](capacity int) StreamXMapper[Type, Type] {
+	return func(inputStream StreamX[Type]) StreamX[Type] {
+		return func(yield func(Type) bool) {
+			items, stop := pump(inputStream, capacity)
+			defer stop()
+
+			ChannelToStream(items)(yield)
+		}
+	}
+}
+
+// TapIndexed calls fn with the zero-based position and value of each item, passing the item through.
+func TapIndexed[Type any](fn func(index int, item Type)) StreamXMapper[Type, Type] {
+	return MapIndexed(func(index int, item Type) Type {
+		fn(index, item)
+		return item
+	})
+}
+
+// MapIndexed transforms each item together with its zero-based position in the input stream.
+func MapIndexed[Input, Output any](fn func(index int, item Input) Output) StreamXMapper[Input, Output] {
+	return func(inputStream StreamX[Input]) StreamX[Output] {
+		return func(yield func(Output) bool) {
+			index := 0
+			inputStream(func(val Input) bool {
+				output := fn(index, val)
+				index++
+				return yield(output)
+			})
+		}
+	}
+}
+
+// FlatMapSlice maps each item to a slice and yields its elements one by one.
+func FlatMapSlice[Input, Output any](mapper func(Input) []Output) StreamXMapper[Input, Output] {
+	return func(inputStream StreamX[Input]) StreamX[Output] {
+		return func(yield func(Output) bool) {
+			inputStream(func(val Input) bool {
+				for _, item := range mapper(val) {
+					if !yield(item) {
+						return false // Stop mid-slice
+					}
+				}
+				return true
+			})
+		}
+	}
+}
+
+// DedupConsecutive drops items equal to the item right before them.
+func DedupConsecutive[Type comparable]() StreamXMapper[Type, Type] {
+	return DedupConsecutiveBy(func(val Type) Type {
+		return val
+	})
+}
+
+// DedupConsecutiveBy drops items whose key equals the key of the item right before them.
+// Only the previous key is kept in memory.
+func DedupConsecutiveBy[Type any, Key comparable](key func(Type) Key) StreamXMapper[Type, Type] {
+	return func(inputStream StreamX[Type]) StreamX[Type] {
+		return func(yield func(Type) bool) {
+			var prev Key
+			started := false
+			inputStream(func(val Type) bool {
+				k := key(val)
+				if started && k == prev {
+					return true // Skip the repeated item
+				}
+				prev, started = k, true
+				return yield(val)
+			})
+		}
+	}
+}
+
+// Sum returns the sum of all items, or zero for an empty stream.
+func Sum[Type Number](stream StreamX[Type]) Type {
+	return Reduce(Type(0), func(acc Type, val Type) Type {
+		return acc + val
+	})(stream)
+}
+
+// Min returns the smallest item, or false for an empty stream.
+func Min[Type cmp.Ordered](stream StreamX[Type]) (Type, bool) {
+	return extreme(stream, func(val, current Type) bool {
+		return val < current
+	})
+}
+
+// Max returns the largest item, or false for an empty stream.
+func Max[Type cmp.Ordered](stream StreamX[Type]) (Type, bool) {
+	return extreme(stream, func(val, current Type) bool {
+		return val > current
+	})
+}
+
+// extreme returns the item that wins every replace comparison against the current pick.
+func extreme[Type any](stream StreamX[Type], replace func(val, current Type) bool) (Type, bool) {
+	var result Type
+	found := false
+	for item := range stream {
+		if !found || replace(item, result) {
+			result, found = item, true
+		}
+	}
+	return result, found
+}
+
+// Average returns the mean of all items accumulated as float64, or false for an empty stream.
+func Average[Type Number](stream StreamX[Type]) (float64, bool) {
+	sum := 0.0
+	count := 0
+	for item := range stream {
+		sum += float64(item)
+		count++
+	}
+
+	if count == 0 {
+		return 0, false
+	}
+	return sum / float64(count), true
+}
+
+// ToMap consumes the stream and builds a map from the computed keys and values.
+// On duplicate keys the later value wins.
+func ToMap[Type any, Key comparable, Value any](keyFn func(Type) Key, valFn func(Type) Value) func(stream StreamX[Type]) map[Key]Value {
+	return func(stream StreamX[Type]) map[Key]Value {
+		result := make(map[Key]Value)
+		for item := range stream {
+			result[keyFn(item)] = valFn(item)
+		}
+		return result
+	}
+}
+
+// SplitWhen groups consecutive items into chunks, starting a new chunk whenever boundary(prev, curr) is true.
+// The final chunk is flushed at the end of the stream.
+func SplitWhen[Type any](boundary func(prev, curr Type) bool) StreamXMapper[Type, []Type] {
+	return AccumulateUntil(func(buffer []Type, next Type) bool {
+		return boundary(buffer[len(buffer)-1], next)
+	})
+}
+
+// AccumulateUntil collects items into a buffer and emits it whenever shouldEmit(buffer, next) is true,
+// starting the next buffer with the incoming item. shouldEmit is only called with a non-empty buffer.
+// The trailing buffer is flushed at the end of the stream.
+func AccumulateUntil[Type any](shouldEmit func(buffer []Type, next Type) bool) StreamXMapper[Type, []Type] {
+	return func(inputStream StreamX[Type]) StreamX[[]Type] {
+		return func(yield func([]Type) bool) {
+			var buffer []Type
+			stopped := false
+			inputStream(func(val Type) bool {
+				if len(buffer) > 0 && shouldEmit(buffer, val) {
+					toEmit := buffer
+					buffer = []Type{val} // Start the next buffer
+					stopped = !yield(toEmit)
+					return !stopped
+				}
+				buffer = append(buffer, val)
+				return true // Continue iterating
+			})
+
+			// If there are remaining items in the buffer, yield them
+			if !stopped && len(buffer) > 0 {
+				yield(buffer)
+			}
+		}
+	}
+}
+
+// First returns the first item and stops the stream immediately, or false for an empty stream.
+func First[Type any](stream StreamX[Type]) (Type, bool) {
+	return Find(func(Type) bool {
+		return true
+	})(stream)
+}
+
+// Last consumes the whole stream and returns its final item, or false for an empty stream.
+func Last[Type any](stream StreamX[Type]) (Type, bool) {
+	return extreme(stream, func(Type, Type) bool {
+		return true
+	})
+}
+
+// Nth returns the item at zero-based index n and stops the stream right after it.
+// It returns false for a negative n or a shorter stream.
+func Nth[Type any](n int) func(stream StreamX[Type]) (Type, bool) {
+	return func(stream StreamX[Type]) (Type, bool) {
+		if n < 0 {
+			var zero Type
+			return zero, false
+		}
+		return First(Drop[Type](n)(stream))
+	}
+}
+
+// MapRetry calls the mapper up to attempts times per item, sleeping backoff between failed attempts,
+// and yields the first success or the last error as a Result.
+func MapRetry[Input, Output any](attempts int, backoff time.Duration, mapper func(Input) (Output, error)) StreamXMapper[Input, Result[Output]] {
+	return MapResult(func(val Input) (Output, error) {
+		output, err := mapper(val)
+		for attempt := 1; err != nil && attempt < attempts; attempt++ {
+			time.Sleep(backoff)
+			output, err = mapper(val)
+		}
+		return output, err
+	})
+}
+
+// MapTimeout runs the mapper for each item with a context that expires after timeout.
+// If the mapper has not returned by then, a Result with context.DeadlineExceeded is yielded.
+// A mapper that ignores its context keeps running in the background until it returns.
+func MapTimeout[Input, Output any](timeout time.Duration, mapper func(context.Context, Input) (Output, error)) StreamXMapper[Input, Result[Output]] {
+	return MapResult(func(val Input) (Output, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		result := make(chan Result[Output], 1)
+		go func() {
+			output, err := mapper(ctx, val)
+			result <- Result[Output]{Value: output, Err: err}
+		}()
+
+		select {
+		case r := <-result:
+			return r.Value, r.Err
+		case <-ctx.Done():
+			var zero Output
+			return zero, ctx.Err()
+		}
+	})
+}
+
+// teeBufferSize is how many items each Tee output may lag behind the fastest one.
+const teeBufferSize = 64
+
+// Tee returns n streams that each yield every item of the source exactly once.
+// The source is run once in a goroutine started by the first output to be iterated.
+// Each output buffers up to teeBufferSize items, so an output that is not consumed
+// eventually blocks the others. An output that stops early no longer receives items,
+// and the source is not pulled again once every output has stopped, although it may already have run
+// up to teeBufferSize items ahead of them. Each output can be iterated once.
+func Tee[Type any](stream StreamX[Type], n int) []StreamX[Type] {
+	channels := make([]chan Type, n)
+	quit := make([]chan struct{}, n)
+	quitOnce := make([]sync.Once, n)
+	for i := range n {
+		channels[i] = make(chan Type, teeBufferSize)
+		quit[i] = make(chan struct{})
+	}
+
+	var start sync.Once
+	run := func() {
+		go func() {
+			defer func() {
+				for _, ch := range channels {
+					close(ch)
+				}
+			}()
+
+			stream(func(val Type) bool {
+				active := 0
+				for i, ch := range channels {
+					// Check quit first, or a stopped output with buffer space could still win the select below
+					select {
+					case <-quit[i]:
+						continue // This output has stopped
+					default:
+					}
+
+					select {
+					case ch <- val:
+						active++
+					case <-quit[i]:
+						// This output has stopped
+					}
+				}
+				return active > 0
+			})
+		}()
+	}
+
+	outputs := make([]StreamX[Type], n)
+	for i := range n {
+		outputs[i] = func(yield func(Type) bool) {
+			start.Do(run)
+			for item := range channels[i] {
+				if !yield(item) {
+					quitOnce[i].Do(func() { close(quit[i]) })
+					return
+				}
+			}
+		}
+	}
+	return outputs
+}
+
+// Broadcast drives the source once and hands every item to each consumer, each running in its own goroutine.
+// Items are delivered unbuffered, so the source advances at the pace of the slowest consumer.
+// A consumer that returns early stops receiving items while the others continue;
+// the source is stopped once every consumer has returned. Broadcast returns after all consumers return.
+func Broadcast[Type any](stream StreamX[Type], consumers ...func(StreamX[Type])) {
+	channels := make([]chan Type, len(consumers))
+	quit := make([]chan struct{}, len(consumers))
+	var wg sync.WaitGroup
+
+	for i, consumer := range consumers {
+		channels[i] = make(chan Type)
+		quit[i] = make(chan struct{})
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(quit[i])
+			consumer(ChannelToStream(channels[i]))
+		}()
+	}
+
+	stream(func(val Type) bool {
+		active := 0
+		for i, ch := range channels {
+			select {
+			case ch <- val:
+				active++
+			case <-quit[i]:
+				// This consumer has returned
+			}
+		}
+		return active > 0
+	})
+
+	for _, ch := range channels {
+		close(ch)
+	}
+	wg.Wait()
+}
+
+// JSONLinesToStream decodes one JSON value per line of r. Blank lines are skipped.
+// Lines that fail to decode are yielded as error Results and the stream continues.
+func JSONLinesToStream[Type any](r io.Reader) StreamX[Result[Type]] {
+	lines := Pipe(LinesFromReaderResult(r), Filter(func(line Result[string]) bool {
+		return line.Err != nil || strings.TrimSpace(line.Value) != ""
+	}))
+
+	return Pipe(lines, Map(func(line Result[string]) Result[Type] {
+		var value Type
+		if line.Err != nil {
+			return Result[Type]{Err: line.Err}
+		}
+		err := json.Unmarshal([]byte(line.Value), &value)
+		return Result[Type]{Value: value, Err: err}
+	}))
+}
+
+// StreamToJSONLines encodes each item as one JSON line to w and stops at the first error.
+func StreamToJSONLines[Type any](w io.Writer) func(stream StreamX[Type]) error {
+	return func(stream StreamX[Type]) error {
+		encoder := json.NewEncoder(w)
+		for item := range stream {
+			if err := encoder.Encode(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// CSVToStream yields each CSV record of r, including the header row, as a Result.
+// Malformed rows are yielded as error Results and the stream continues; read errors end the stream.
+func CSVToStream(r io.Reader) StreamX[Result[[]string]] {
+	return func(yield func(Result[[]string]) bool) {
+		reader := csv.NewReader(r)
+		for {
+			record, err := reader.Read()
+			if err == io.EOF {
+				return
+			}
+			if !yield(Result[[]string]{Value: record, Err: err}) {
+				return
+			}
+
+			var parseErr *csv.ParseError
+			if err != nil && !errors.As(err, &parseErr) {
+				return // Not a per-row error, the reader cannot continue
+			}
+		}
+	}
+}
+
+// StreamToCSV writes each record to w as CSV and returns the first write error.
+func StreamToCSV(w io.Writer) func(stream StreamX[[]string]) error {
+	return func(stream StreamX[[]string]) error {
+		writer := csv.NewWriter(w)
+		for record := range stream {
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	}
+}
+
+// MapFilter transforms each item and emits the result only when fn also returns true.
+func MapFilter[Input, Output any](fn func(Input) (Output, bool)) StreamXMapper[Input, Output] {
+	return func(inputStream StreamX[Input]) StreamX[Output] {
+		return func(yield func(Output) bool) {
+			inputStream(func(val Input) bool {
+				if output, ok := fn(val); ok {
+					return yield(output)
+				}
+				return true // Continue iterating
+			})
+		}
+	}
+}
+
+// Intersperse inserts separator between every pair of consecutive items.
+func Intersperse[Type any](separator Type) StreamXMapper[Type, Type] {
+	return func(inputStream StreamX[Type]) StreamX[Type] {
+		return func(yield func(Type) bool) {
+			first := true
+			inputStream(func(val Type) bool {
+				if !first && !yield(separator) {
+					return false
+				}
+				first = false
+				return yield(val)
+			})
+		}
+	}
+}
+
+// FlattenStreams drains each inner stream in order into the output.
+func FlattenStreams[Type any]() StreamXMapper[StreamX[Type], Type] {
+	return FlatMap(func(inner StreamX[Type]) StreamX[Type] {
+		return inner
+	})
+}
+
+// WindowByTime groups the items arriving within each fixed interval of duration, measured from
+// the start of the stream, and emits them when the interval closes. Windows without items are
+// emitted as empty slices when emitEmpty is true and skipped otherwise. A non-empty partial
+// window is flushed at the end of the stream.
+func WindowByTime[Type any](duration time.Duration, emitEmpty bool) StreamXMapper[Type, []Type] {
+	return func(inputStream StreamX[Type]) StreamX[[]Type] {
+		return func(yield func([]Type) bool) {
+			items, stop := pump(inputStream, 0)
+			defer stop()
+
+			ticker := time.NewTicker(duration)
+			defer ticker.Stop()
+
+			window := make([]Type, 0)
+			for {
+				select {
+				case val, ok := <-items:
+					if !ok {
+						if len(window) > 0 {
+							yield(window) // Flush the partial window
+						}
+						return
+					}
+					window = append(window, val)
+				case <-ticker.C:
+					if len(window) == 0 && !emitEmpty {
+						continue // Skip the empty window
+					}
+					toEmit := window
+					window = make([]Type, 0) // Start the next window
+					if !yield(toEmit) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+// SampleEvery yields the items at indices 0, n, 2n, ... and drops the rest.
+// An n of 1 or less passes every item through.
+func SampleEvery[Type any](n int) StreamXMapper[Type, Type] {
+	return func(inputStream StreamX[Type]) StreamX[Type] {
+		return func(yield func(Type) bool) {
+			index := 0
+			inputStream(func(val Type) bool {
+				keep := n <= 1 || index%n == 0
+				index++
+				if keep {
+					return yield(val)
+				}
+				return true // Continue iterating
+			})
+		}
+	}
+}
+
+// SampleByTime yields at most one item per interval, dropping items that arrive too soon after the last emitted one.
+func SampleByTime[Type any](interval time.Duration) StreamXMapper[Type, Type] {
+	return func(inputStream StreamX[Type]) StreamX[Type] {
+		return func(yield func(Type) bool) {
+			var last time.Time
+			inputStream(func(val Type) bool {
+				if !last.IsZero() && time.Since(last) < interval {
+					return true // Too soon, drop the item
+				}
+				last = time.Now()
+				return yield(val)
+			})
+		}
+	}
+}
+
+// Compose2 combines two mappers into one, allowing the type to change between stages.
+func Compose2[A, B, C any](m1 StreamXMapper[A, B], m2 StreamXMapper[B, C]) StreamXMapper[A, C] {
+	return func(inputStream StreamX[A]) StreamX[C] {
+		return m2(m1(inputStream))
+	}
+}
+
+// Compose3 combines three mappers into one, allowing the type to change between stages.
+func Compose3[A, B, C, D any](m1 StreamXMapper[A, B], m2 StreamXMapper[B, C], m3 StreamXMapper[C, D]) StreamXMapper[A, D] {
+	return Compose2(Compose2(m1, m2), m3)
+}
+
+// Compose4 combines four mappers into one, allowing the type to change between stages.
+func Compose4[A, B, C, D, E any](m1 StreamXMapper[A, B], m2 StreamXMapper[B, C], m3 StreamXMapper[C, D], m4 StreamXMapper[D, E]) StreamXMapper[A, E] {
+	return Compose2(Compose3(m1, m2, m3), m4)
+}
+
+// Then chains m2 after m1, reading left to right. Nest it to build longer type-changing chains,
+// e.g. Then(Then(m1, m2), m3).
+func Then[A, B, C any](m1 StreamXMapper[A, B], m2 StreamXMapper[B, C]) StreamXMapper[A, C] {
+	return Compose2(m1, m2)
+}
+
+// Recover applies the mapper and recovers from its panics. On panic, onPanic decides
+// whether to emit a fallback value (true) or skip the item (false).
+func Recover[Input, Output any](mapper func(Input) Output, onPanic func(recovered any, input Input) (Output, bool)) StreamXMapper[Input, Output] {
+	return MapFilter(func(val Input) (output Output, ok bool) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				output, ok = onPanic(recovered, val)
+			}
+		}()
+		return mapper(val), true
+	})
+}
+
+// Meter passes items through and, once the stream finishes or is stopped early,
+// reports how many items flowed and how long the stream ran.
+func Meter[Type any](onComplete func(count int, elapsed time.Duration)) StreamXMapper[Type, Type] {
+	return func(inputStream StreamX[Type]) StreamX[Type] {
+		return func(yield func(Type) bool) {
+			count := 0
+			started := time.Now()
+			defer func() {
+				onComplete(count, time.Since(started))
+			}()
+
+			inputStream(func(val Type) bool {
+				count++
+				return yield(val)
+			})
+		}
+	}
+}
+
+// Progress passes items through, calling fn with the running count every `every` items
+// and once more when the stream finishes, unless that count was just reported.
+// An every of 0 or less reports only at the end.
+func Progress[Type any](every int, fn func(count int)) StreamXMapper[Type, Type] {
+	return func(inputStream StreamX[Type]) StreamX[Type] {
+		return func(yield func(Type) bool) {
+			count := 0
+			reported := -1
+			defer func() {
+				if reported != count {
+					fn(count)
+				}
+			}()
+
+			inputStream(func(val Type) bool {
+				count++
+				if every > 0 && count%every == 0 {
+					fn(count)
+					reported = count
+				}
+				return yield(val)
+			})
+		}
+	}
+}
+
+// WriteTo writes each chunk to w, stopping at the first write error, and returns the total bytes written.
+// If w has a Flush() error method, such as *bufio.Writer, it is flushed before returning.
+func WriteTo(w io.Writer) func(stream StreamX[[]byte]) (int64, error) {
+	return func(stream StreamX[[]byte]) (int64, error) {
+		var total int64
+		var err error
+		for chunk := range stream {
+			var n int
+			n, err = w.Write(chunk)
+			total += int64(n)
+			if err != nil {
+				break
+			}
+		}
+
+		if flusher, ok := w.(interface{ Flush() error }); ok {
+			if flushErr := flusher.Flush(); err == nil {
+				err = flushErr
+			}
+		}
+		return total, err
+	}
+}
+
+// GroupAdjacent emits a group of consecutive items each time the key changes, holding at most one group in memory.
+// On input sorted by key this is the streaming equivalent of GroupBy.
+func GroupAdjacent[Type any, Key comparable](key func(Type) Key) StreamXMapper[Type, Group[Key, Type]] {
+	return func(inputStream StreamX[Type]) StreamX[Group[Key, Type]] {
+		return func(yield func(Group[Key, Type]) bool) {
+			var current Group[Key, Type]
+			inputStream(func(val Type) bool {
+				k := key(val) // Computed once per item
+				if len(current.Items) > 0 && k != current.Key {
+					toEmit := current
+					current = Group[Key, Type]{} // Reset the group
+					if !yield(toEmit) {
+						return false
+					}
+				}
+				current.Key = k
+				current.Items = append(current.Items, val)
+				return true
+			})
+
+			// Flush the last group
+			if len(current.Items) > 0 {
+				yield(current)
+			}
+		}
+	}
+}
+
+// Cache records the items of the first complete pass over the stream and replays them from memory
+// on later iterations. A pass that stops early is not cached, so the next iteration runs the source again.
+// Iterations never wait for each other: one that starts, nested or concurrently, before the first pass
+// completes runs the source itself without caching, so a one-shot source is split between them.
+// The whole stream is buffered in memory.
+func Cache[Type any](stream StreamX[Type]) StreamX[Type] {
+	return CacheTTL(stream, 0)
+}
+
+// CacheTTL is like Cache, but once ttl has elapsed since the last complete pass the next iteration
+// runs the source again and refreshes the cache. A ttl of 0 or less never expires. Only one pass
+// refreshes the cache at a time, and no iteration ever waits for it: an iteration that starts during
+// a refresh, including a nested one, replays the previous snapshot if there is one and otherwise
+// runs the source without caching.
+func CacheTTL[Type any](stream StreamX[Type], ttl time.Duration) StreamX[Type] {
+	var mu sync.Mutex      // Guards cached, cachedAt and complete
+	var refresh sync.Mutex // Held by the pass that records a new snapshot
+	var cached []Type
+	var cachedAt time.Time
+	complete := false
+
+	snapshot := func() (items []Type, ok, fresh bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		return cached, complete, complete && (ttl <= 0 || time.Since(cachedAt) < ttl)
+	}
+
+	return func(yield func(Type) bool) {
+		if items, _, fresh := snapshot(); fresh {
+			SliceToStream(items)(yield)
+			return
+		}
+
+		if !refresh.TryLock() {
+			// Another pass is refreshing; serve the stale snapshot rather than wait for it
+			if items, ok, _ := snapshot(); ok {
+				SliceToStream(items)(yield)
+				return
+			}
+			stream(yield)
+			return
+		}
+		// A refresh may have completed since the first check
+		if items, _, fresh := snapshot(); fresh {
+			refresh.Unlock()
+			SliceToStream(items)(yield)
+			return
+		}
+		defer refresh.Unlock()
+
+		recorded := make([]Type, 0)
+		for item := range stream {
+			recorded = append(recorded, item)
+			if !yield(item) {
+				return // Partial pass, do not cache
+			}
+		}
+
+		mu.Lock()
+		cached, cachedAt, complete = recorded, time.Now(), true
+		mu.Unlock()
+	}
+}
+
+// Once wraps the stream so that iterating it a second time panics instead of silently yielding nothing.
+func Once[Type any](stream StreamX[Type]) StreamX[Type] {
+	var used sync.Once
+	return func(yield func(Type) bool) {
+		first := false
+		used.Do(func() {
+			first = true
+		})
+		if !first {
+			panic("streamx: stream wrapped with Once was iterated more than once")
+		}
+		stream(yield)
+	}
+}
+
+// Pull converts the stream into a pull-style iterator. next returns the next item and whether it is valid.
+// stop must be called once the caller is done; it is safe to call more than once.
+func Pull[Type any](stream StreamX[Type]) (next func() (Type, bool), stop func()) {
+	return iter.Pull(iter.Seq[Type](stream))
+}
+
+// FlatMapBounded is like FlatMapSlice but emits at most maxPerInput elements per input, dropping the excess.
+// A maxPerInput of 0 or less means unbounded.
+func FlatMapBounded[Input, Output any](maxPerInput int, mapper func(Input) []Output) StreamXMapper[Input, Output] {
+	return FlatMapSlice(func(val Input) []Output {
+		outputs := mapper(val)
+		if maxPerInput > 0 && len(outputs) > maxPerInput {
+			return outputs[:maxPerInput]
+		}
+		return outputs
+	})
+}
+
+// Coalesce replaces empty items with fallback(prev), where prev is the last non-empty item (forward fill).
+// Empty items before the first non-empty one are skipped when skipLeading is true and emitted unchanged otherwise.
+func Coalesce[Type any](isEmpty func(Type) bool, fallback func(prev Type) Type, skipLeading bool) StreamXMapper[Type, Type] {
+	return func(inputStream StreamX[Type]) StreamX[Type] {
+		return func(yield func(Type) bool) {
+			var prev Type
+			seen := false
+			inputStream(func(val Type) bool {
+				if !isEmpty(val) {
+					prev, seen = val, true
+					return yield(val)
+				}
+				if seen {
+					return yield(fallback(prev))
+				}
+				if skipLeading {
+					return true // No previous item to fill from
+				}
+				return yield(val)
+			})
+		}
+	}
+}
+
+// MovingAverage yields the average of the last size values, starting once size values have been seen.
+// It keeps a running sum over a ring buffer, so each item costs O(1).
+func MovingAverage(size int) StreamXMapper[float64, float64] {
+	if size <= 0 {
+		panic("streamx: MovingAverage size must be positive")
+	}
+
+	return func(inputStream StreamX[float64]) StreamX[float64] {
+		return func(yield func(float64) bool) {
+			ring := make([]float64, size)
+			sum := 0.0
+			seen := 0
+			inputStream(func(val float64) bool {
+				slot := seen % size
+				sum += val - ring[slot] // Replace the oldest value
+				ring[slot] = val
+				seen++
+
+				if seen < size {
+					return true // Window not full yet
+				}
+				return yield(sum / float64(size))
+			})
+		}
+	}
+}
+
+// Finally passes items through and runs fn once the stream finishes, whether it completed or was stopped early.
+func Finally[Type any](fn func()) StreamXMapper[Type, Type] {
+	return func(inputStream StreamX[Type]) StreamX[Type] {
+		return func(yield func(Type) bool) {
+			defer fn()
+			inputStream(yield)
+		}
+	}
+}
+
+// Inspect passes items through and reports for each one whether downstream accepted it
+// or signalled to stop. If onYield is nil, the label, item and outcome are printed like Log.
+func Inspect[Type any](label string, onYield func(item Type, accepted bool)) StreamXMapper[Type, Type] {
+	if onYield == nil {
+		onYield = func(item Type, accepted bool) {
+			fmt.Println(label, item, accepted)
+		}
+	}
+
+	return func(inputStream StreamX[Type]) StreamX[Type] {
+		return func(yield func(Type) bool) {
+			inputStream(func(val Type) bool {
+				accepted := yield(val)
+				onYield(val, accepted)
+				return accepted
+			})
+		}
+	}
+}
+
+// Backoff produces an infinite stream of delays starting at base, multiplied by factor each step and capped at max.
+func Backoff(base time.Duration, factor float64, max time.Duration) StreamX[time.Duration] {
+	return Iterate(min(base, max), func(delay time.Duration) time.Duration {
+		next := float64(delay) * factor
+		if next >= float64(max) {
+			return max
+		}
+		return time.Duration(next)
+	})
+}
+
+// DistinctWindow drops items equal to one of the previous window input items.
+// A value may appear again once it has fallen out of the window, so memory stays bounded by window.
+func DistinctWindow[Type comparable](window int) StreamXMapper[Type, Type] {
+	if window <= 0 {
+		panic("streamx: DistinctWindow window must be positive")
+	}
+
+	return func(inputStream StreamX[Type]) StreamX[Type] {
+		return func(yield func(Type) bool) {
+			ring := make([]Type, 0, window)
+			counts := make(map[Type]int)
+			next := 0
+			inputStream(func(val Type) bool {
+				duplicate := counts[val] > 0
+
+				// Push the item into the window, evicting the oldest one when full
+				if len(ring) < window {
+					ring = append(ring, val)
+				} else {
+					oldest := ring[next]
+					if counts[oldest]--; counts[oldest] == 0 {
+						delete(counts, oldest)
+					}
+					ring[next] = val
+					next = (next + 1) % window
+				}
+				counts[val]++
+
+				if duplicate {
+					return true // Skip duplicates
+				}
+				return yield(val)
+			})
+		}
+	}
+}
+
+// FanOut consumes the stream by dispatching each item to one of concurrency worker goroutines,
+// blocking the source while all workers are busy. It returns once all items are processed.
+// If a worker panics, no further items are dispatched and the panic is re-raised in the caller.
+func FanOut[Type any](concurrency int, worker func(Type)) func(stream StreamX[Type]) {
+	return func(stream StreamX[Type]) {
+		items := make(chan Type)
+		failed := make(chan struct{})
+		var failOnce sync.Once
+		var recovered any
+		var wg sync.WaitGroup
+
+		for range max(concurrency, 1) {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() {
+					if r := recover(); r != nil {
+						failOnce.Do(func() {
+							recovered = r
+							close(failed)
+						})
+					}
+				}()
+
+				for item := range items {
+					worker(item)
+				}
+			}()
+		}
+
+		stream(func(val Type) bool {
+			select {
+			case items <- val:
+				return true
+			case <-failed:
+				return false // A worker panicked
+			}
+		})
+
+		close(items)
+		wg.Wait()
+
+		if recovered != nil {
+			panic(recovered)
+		}
+	}
+}
+
+// Ticker yields the current time on every tick of interval until ctx is done or downstream stops.
+func Ticker(ctx context.Context, interval time.Duration) StreamX[time.Time] {
+	return func(yield func(time.Time) bool) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case tick := <-ticker.C:
+				if !yield(tick) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// SplitOn emits the items between occurrences of sentinel as segments, without the sentinel itself.
+// Like strings.Split, consecutive sentinels produce empty segments and a trailing sentinel is followed
+// by a final empty segment, so [1 0] yields [1] and []. Unlike strings.Split, an empty stream yields nothing.
+func SplitOn[Type comparable](sentinel Type) StreamXMapper[Type, []Type] {
+	return func(inputStream StreamX[Type]) StreamX[[]Type] {
+		return func(yield func([]Type) bool) {
+			segment := make([]Type, 0)
+			seen, stopped := false, false
+			inputStream(func(val Type) bool {
+				if val != sentinel {
+					segment = append(segment, val)
+					return true // Continue iterating
+				}
+				seen = true
+				toEmit := segment
+				segment = make([]Type, 0) // Start the next segment
+				stopped = !yield(toEmit)
+				return !stopped
+			})
+
+			// Flush the last segment, which is empty after a trailing sentinel
+			if !stopped && (seen || len(segment) > 0) {
+				yield(segment)
+			}
+		}
+	}
+}
+
+// Cast type-asserts each item to Type, yielding an error Result for items of another type.
+func Cast[Type any]() StreamXMapper[any, Result[Type]] {
+	return MapResult(func(val any) (Type, error) {
+		typed, ok := val.(Type)
+		if !ok {
+			return typed, fmt.Errorf("streamx: cannot cast %T to %v", val, reflect.TypeFor[Type]())
+		}
+		return typed, nil
+	})
+}
+
+// CastOrSkip type-asserts each item to Type and drops items of another type.
+func CastOrSkip[Type any]() StreamXMapper[any, Type] {
+	return MapFilter(func(val any) (Type, bool) {
+		typed, ok := val.(Type)
+		return typed, ok
+	})
+}
+
+// UnwrapOrSkip yields the values of successful results and drops errors. It is the same as FilterErrors.
+func UnwrapOrSkip[Type any]() StreamXMapper[Result[Type], Type] {
+	return FilterErrors[Type]()
+}
+
+// MustUnwrap yields the values of successful results and panics on the first error.
+func MustUnwrap[Type any]() StreamXMapper[Result[Type], Type] {
+	return Map(func(val Result[Type]) Type {
+		if val.Err != nil {
+			panic(val.Err)
+		}
+		return val.Value
+	})
+}
+
+// CollectFirstError collects the values of successful results until the first error,
+// then stops the stream and returns the values collected so far together with that error.
+func CollectFirstError[Type any](stream StreamX[Result[Type]]) (values []Type, err error) {
+	values = make([]Type, 0)
+	for item := range stream {
+		if item.Err != nil {
+			return values, item.Err
+		}
+		values = append(values, item.Value)
+	}
+	return values, nil
+}
+
+// BatchParallel groups items into batches of size, runs the mapper on up to concurrency batches at once,
+// and yields the flattened outputs in batch order. The final partial batch is processed as well.
+func BatchParallel[Input, Output any](size, concurrency int, mapper func([]Input) []Output) StreamXMapper[Input, Output] {
+	return Compose3(
+		Batch[Input](size),
+		MapParallel(concurrency, mapper),
+		Flat[Output](),
+	)
+}
+
+// Delay sleeps for d before yielding each item.
+func Delay[Type any](d time.Duration) StreamXMapper[Type, Type] {
+	return func(inputStream StreamX[Type]) StreamX[Type] {
+		return func(yield func(Type) bool) {
+			inputStream(func(val Type) bool {
+				time.Sleep(d)
+				return yield(val)
+			})
+		}
+	}
+}
+
+// StartWith yields the given items before the input stream.
+func StartWith[Type any](items ...Type) StreamXMapper[Type, Type] {
+	return func(inputStream StreamX[Type]) StreamX[Type] {
+		return Concat(SliceToStream(items), inputStream)
+	}
+}
+
+// EndWith yields the given items after the input stream, but only if downstream did not stop early.
+func EndWith[Type any](items ...Type) StreamXMapper[Type, Type] {
+	return func(inputStream StreamX[Type]) StreamX[Type] {
+		return Concat(inputStream, SliceToStream(items))
+	}
+}
+
+// BranchMap transforms items passing the condition with ifTrue and all other items with ifFalse.
+func BranchMap[Input, Output any](condition func(Input) bool, ifTrue, ifFalse func(Input) Output) StreamXMapper[Input, Output] {
+	return Map(func(val Input) Output {
+		if condition(val) {
+			return ifTrue(val)
+		}
+		return ifFalse(val)
+	})
+}
+
+// MapEntries yields every key-value pair of each input map. The order within a map is not specified.
+func MapEntries[Key comparable, Value any]() StreamXMapper[map[Key]Value, Entry[Key, Value]] {
+	return func(inputStream StreamX[map[Key]Value]) StreamX[Entry[Key, Value]] {
+		return func(yield func(Entry[Key, Value]) bool) {
+			inputStream(func(val map[Key]Value) bool {
+				for k, v := range val {
+					if !yield(Entry[Key, Value]{Key: k, Value: v}) {
+						return false
+					}
+				}
+				return true
+			})
+		}
+	}
+}
+
+// Enumerate wraps each item with its zero-based position in the stream it is applied to.
+func Enumerate[Type any]() StreamXMapper[Type, Indexed[Type]] {
+	return MapIndexed(func(index int, item Type) Indexed[Type] {
+		return Indexed[Type]{Index: index, Value: item}
+	})
+}
+
+// BatchResults groups successful values into batches of size. When an error arrives, the pending batch
+// is flushed first and the error is then yielded on its own, keeping errors in order relative to the data.
+func BatchResults[Type any](size int) StreamXMapper[Result[Type], Result[[]Type]] {
+	return func(inputStream StreamX[Result[Type]]) StreamX[Result[[]Type]] {
+		return func(yield func(Result[[]Type]) bool) {
+			var batched []Type
+			stopped := false
+			flush := func() bool {
+				toEmit := batched
+				batched = nil // Reset the batch
+				stopped = !yield(Result[[]Type]{Value: toEmit})
+				return !stopped
+			}
+
+			inputStream(func(val Result[Type]) bool {
+				if val.Err != nil {
+					if len(batched) > 0 && !flush() {
+						return false
+					}
+					stopped = !yield(Result[[]Type]{Err: val.Err})
+					return !stopped
+				}
+
+				batched = append(batched, val.Value)
+				if len(batched) >= size {
+					return flush()
+				}
+				return true // Continue iterating
+			})
+
+			// If there are remaining items in the batch, yield them
+			if !stopped && len(batched) > 0 {
+				flush()
+			}
+		}
+	}
+}
+
+// TakeWeighted passes items through until their cumulative weight would exceed maxWeight, then stops the input.
+// The item that would exceed the limit is not emitted.
+func TakeWeighted[Type any](maxWeight int, weight func(Type) int) StreamXMapper[Type, Type] {
+	return func(inputStream StreamX[Type]) StreamX[Type] {
+		return func(yield func(Type) bool) {
+			total := 0
+			inputStream(func(val Type) bool {
+				total += weight(val)
+				if total > maxWeight {
+					return false // Limit reached, stop the input
+				}
+				return yield(val)
+			})
+		}
+	}
+}
+
+// TakeBytes passes chunks through until their cumulative size would exceed maxBytes, then stops the input.
+// If sizeOf is nil, the length of each chunk is used.
+func TakeBytes(maxBytes int, sizeOf func([]byte) int) StreamXMapper[[]byte, []byte] {
+	if sizeOf == nil {
+		sizeOf = func(chunk []byte) int {
+			return len(chunk)
+		}
+	}
+	return TakeWeighted(maxBytes, sizeOf)
+}
+
+// StreamEqual reports whether both streams yield the same items in the same order and have the same length.
+// It pulls both streams in lockstep and stops at the first difference.
+func StreamEqual[Type comparable](a, b StreamX[Type]) bool {
+	nextA, stopA := Pull(a)
+	defer stopA()
+	nextB, stopB := Pull(b)
+	defer stopB()
+
+	for {
+		valA, okA := nextA()
+		valB, okB := nextB()
+		if okA != okB || valA != valB {
+			return false
+		}
+		if !okA {
+			return true // Both streams ended together
+		}
+	}
+}
+
+// DefaultIfEmpty passes the input through unchanged, or yields defaults if the input yields nothing at all.
+func DefaultIfEmpty[Type any](defaults ...Type) StreamXMapper[Type, Type] {
+	return func(inputStream StreamX[Type]) StreamX[Type] {
+		return func(yield func(Type) bool) {
+			empty := true
+			inputStream(func(val Type) bool {
+				empty = false
+				return yield(val)
+			})
+
+			if empty {
+				SliceToStream(defaults)(yield)
+			}
+		}
+	}
+}
+
+// Catch yields the values of successful results. For each error, handler may provide a
+// replacement value (true) or have the item skipped (false).
+func Catch[Type any](handler func(error) (Type, bool)) StreamXMapper[Result[Type], Type] {
+	return MapFilter(func(val Result[Type]) (Type, bool) {
+		if val.Err != nil {
+			return handler(val.Err)
+		}
+		return val.Value, true
+	})
+}
+
+// route runs the stream in a goroutine, started by the first output to be iterated, and sends each item
+// to the output chosen by pick. Items are delivered unbuffered, so every output must be consumed
+// concurrently. Items picked for an output that has stopped early are dropped, and the source is
+// stopped once every output has stopped. Each output can be iterated once.
+func route[Type any](stream StreamX[Type], n int, pick func(index int, item Type) int) []StreamX[Type] {
+	channels := make([]chan Type, n)
+	quit := make([]chan struct{}, n)
+	quitOnce := make([]sync.Once, n)
+	var stopped atomic.Int32
+	for i := range n {
+		channels[i] = make(chan Type)
+		quit[i] = make(chan struct{})
+	}
+
+	var start sync.Once
+	run := func() {
+		go func() {
+			defer func() {
+				for _, ch := range channels {
+					close(ch)
+				}
+			}()
+
+			index := 0
+			stream(func(val Type) bool {
+				i := pick(index, val)
+				index++
+				select {
+				case channels[i] <- val:
+				case <-quit[i]:
+					// This output has stopped, drop the item
+				}
+				return int(stopped.Load()) < n
+			})
+		}()
+	}
+
+	outputs := make([]StreamX[Type], n)
+	for i := range n {
+		outputs[i] = func(yield func(Type) bool) {
+			start.Do(run)
+			for item := range channels[i] {
+				if !yield(item) {
+					quitOnce[i].Do(func() {
+						close(quit[i])
+						stopped.Add(1)
+					})
+					return
+				}
+			}
+		}
+	}
+	return outputs
+}
+
+// PartitionStream lazily splits the stream into items satisfying the condition and the rest.
+// The source runs in a goroutine started when either stream is first iterated, and it blocks until
+// the item it holds is received, so both streams must be consumed concurrently. The goroutine
+// exits when the source ends or both streams have stopped early.
+func PartitionStream[Type any](condition func(Type) bool) func(stream StreamX[Type]) (matched StreamX[Type], rest StreamX[Type]) {
+	return func(stream StreamX[Type]) (StreamX[Type], StreamX[Type]) {
+		outputs := route(stream, 2, func(_ int, val Type) int {
+			if condition(val) {
+				return 0
+			}
+			return 1
+		})
+		return outputs[0], outputs[1]
+	}
+}
+
+// RoundRobin distributes the items across n streams in turn, so each item goes to exactly one of them.
+// The source runs in a goroutine started when any stream is first iterated, and it blocks until the
+// item it holds is received, so all streams must be consumed concurrently. Items for a stream that
+// stopped early are dropped, and the source stops once every stream has stopped.
+func RoundRobin[Type any](stream StreamX[Type], n int) []StreamX[Type] {
+	return route(stream, n, func(index int, _ Type) int {
+		return index % n
+	})
+}
+
+// MergeChannels yields items from every inner channel received on chans, reading them concurrently as
+// they arrive. The stream ends once chans is closed and every inner channel is drained and closed.
+func MergeChannels[Type any](chans <-chan (<-chan Type)) StreamX[Type] {
+	return func(yield func(Type) bool) {
+		items := make(chan Type)
+		done := make(chan struct{})
+		var wg sync.WaitGroup
+
+		forward := func(ch <-chan Type) {
+			defer wg.Done()
+			for {
+				select {
+				case val, ok := <-ch:
+					if !ok {
+						return
+					}
+					select {
+					case items <- val:
+					case <-done:
+						return // Consumer stopped
+					}
+				case <-done:
+					return // Consumer stopped
+				}
+			}
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case ch, ok := <-chans:
+					if !ok {
+						return
+					}
+					wg.Add(1)
+					go forward(ch)
+				case <-done:
+					return // Consumer stopped
+				}
+			}
+		}()
+
+		// Close the output once chans is closed and every inner channel is drained
+		go func() {
+			wg.Wait()
+			close(items)
+		}()
+
+		defer func() {
+			close(done)
+			wg.Wait()
+		}()
+
+		for item := range items {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// DistinctByTime drops an item if the same value was emitted less than window ago.
+// Expired entries are evicted at most once per window, so memory is bounded by the values seen within about two windows.
+func DistinctByTime[Type comparable](window time.Duration) StreamXMapper[Type, Type] {
+	return func(inputStream StreamX[Type]) StreamX[Type] {
+		return func(yield func(Type) bool) {
+			lastEmitted := make(map[Type]time.Time)
+			lastSweep := time.Now()
+			inputStream(func(val Type) bool {
+				now := time.Now()
+				if now.Sub(lastSweep) >= window {
+					for k, at := range lastEmitted {
+						if now.Sub(at) >= window {
+							delete(lastEmitted, k) // Evict expired entries
+						}
+					}
+					lastSweep = now
+				}
+
+				if at, ok := lastEmitted[val]; ok && now.Sub(at) < window {
+					return true // Skip recent duplicates
+				}
+				lastEmitted[val] = now
+				return yield(val)
+			})
+		}
+	}
+}
+
+// StateMap threads a state through the stream: for each input step returns the next state,
+// an output, and whether that output should be emitted.
+func StateMap[Input, State, Output any](initial State, step func(State, Input) (State, Output, bool)) StreamXMapper[Input, Output] {
+	return func(inputStream StreamX[Input]) StreamX[Output] {
+		return func(yield func(Output) bool) {
+			state := initial
+			inputStream(func(val Input) bool {
+				next, output, emit := step(state, val)
+				state = next
+				if emit {
+					return yield(output)
+				}
+				return true // Continue iterating
+			})
+		}
+	}
+}
+
+// ChunkByKey emits a chunk each time the key differs from the previous item's key, so [a a b a] yields three chunks.
+// It is an alias for GroupAdjacent named for run-length segmentation.
+func ChunkByKey[Type any, Key comparable](key func(Type) Key) StreamXMapper[Type, Group[Key, Type]] {
+	return GroupAdjacent(key)
+}
+
+// Builder wraps a stream for fluent, left-to-right pipeline construction: From(stream).Filter(...).Take(10).Collect().
+// Go methods cannot introduce type parameters, so type-changing steps are package-level helpers such as MapTo and Via.
+type Builder[Type any] struct {
+	stream StreamX[Type]
+}
+
+// From starts a fluent pipeline over stream.
+func From[Type any](stream StreamX[Type]) Builder[Type] {
+	return Builder[Type]{stream: stream}
+}
+
+// Filter keeps only the items that satisfy condition.
+func (b Builder[Type]) Filter(condition func(Type) bool) Builder[Type] {
+	return From(Filter(condition)(b.stream))
+}
+
+// Map transforms each item without changing its type; use MapTo to change it.
+func (b Builder[Type]) Map(mapper func(Type) Type) Builder[Type] {
+	return From(Map(mapper)(b.stream))
+}
+
+// Take keeps at most the first n items.
+func (b Builder[Type]) Take(n int) Builder[Type] {
+	return From(Take[Type](n)(b.stream))
+}
+
+// Drop skips the first n items.
+func (b Builder[Type]) Drop(n int) Builder[Type] {
+	return From(Drop[Type](n)(b.stream))
+}
+
+// Tap calls fn for each item as it passes through.
+func (b Builder[Type]) Tap(fn func(Type)) Builder[Type] {
+	return From(Tap(func(val Type) struct{} {
+		fn(val)
+		return struct{}{}
+	})(b.stream))
+}
+
+// Apply runs the same-type mappers over the stream in order.
+func (b Builder[Type]) Apply(mappers ...StreamXMapper[Type, Type]) Builder[Type] {
+	return From(Pipeline(mappers...)(b.stream))
+}
+
+// Stream returns the underlying stream.
+func (b Builder[Type]) Stream() StreamX[Type] {
+	return b.stream
+}
+
+// Collect consumes the stream into a slice.
+func (b Builder[Type]) Collect() []Type {
+	return StreamToSlice(b.stream)
+}
+
+// ForEach calls fn for each item until it returns false.
+func (b Builder[Type]) ForEach(fn func(Type) bool) {
+	ForEach(fn)(b.stream)
+}
+
+// MapTo transforms each item of the builder into a new type.
+func MapTo[Input, Output any](b Builder[Input], mapper func(Input) Output) Builder[Output] {
+	return From(Map(mapper)(b.stream))
+}
+
+// BatchOf groups the builder's items into slices of size.
+func BatchOf[Type any](b Builder[Type], size int) Builder[[]Type] {
+	return From(Batch[Type](size)(b.stream))
+}
+
+// Via applies any mapper to the builder, including ones that change the item type.
+func Via[Input, Output any](b Builder[Input], mapper StreamXMapper[Input, Output]) Builder[Output] {
+	return From(mapper(b.stream))
+}
+
+// FlatFilter flattens each input slice and yields only the elements that keep accepts, in a single stage.
+// Like Flat, nil and empty slices contribute nothing.
+func FlatFilter[Output any](keep func(Output) bool) StreamXMapper[[]Output, Output] {
+	return func(inputStream StreamX[[]Output]) StreamX[Output] {
+		return func(yield func(Output) bool) {
+			inputStream(func(val []Output) bool {
+				for _, item := range val {
+					if keep(item) && !yield(item) {
+						return false // Consumer stopped
+					}
+				}
+				return true
+			})
+		}
+	}
+}
+
+// ForEachWithRetry calls fn for each item, retrying a failing item up to attempts times in total.
+// If an item still fails, the stream is stopped and the last error is returned; items that succeeded are never retried.
+func ForEachWithRetry[Type any](attempts int, fn func(Type) error) func(stream StreamX[Type]) error {
+	return func(stream StreamX[Type]) error {
+		var err error
+		stream(func(val Type) bool {
+			err = fn(val)
+			for attempt := 1; err != nil && attempt < attempts; attempt++ {
+				err = fn(val)
+			}
+			return err == nil
+		})
+		return err
+	}
+}
+
+// Combine composes same-type mappers left to right into one mapper. Combine() is the identity.
+func Combine[Type any](mappers ...StreamXMapper[Type, Type]) StreamXMapper[Type, Type] {
+	return Pipeline(mappers...)
+}
+
 func main() {
 	// Create a StreamX From the input slice
 	stream0 := SliceToStream([]int{1, 2, 3, 4, 5, 6, 7, 8, 9})
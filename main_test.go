@@ -0,0 +1,432 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// finishes fails the test if fn does not return within a second.
+func finishes(t *testing.T, fn func()) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn()
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("timed out")
+	}
+}
+
+// noLeaks runs fn like finishes and then fails if goroutines started meanwhile are still running.
+func noLeaks(t *testing.T, fn func()) {
+	t.Helper()
+	before := runtime.NumGoroutine()
+	finishes(t, fn)
+	for deadline := time.Now().Add(time.Second); runtime.NumGoroutine() > before; {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d goroutines leaked", runtime.NumGoroutine()-before)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// endless returns an infinite source and a channel that is closed once the source returns.
+func endless() (StreamX[int], <-chan struct{}) {
+	stopped := make(chan struct{})
+	return func(yield func(int) bool) {
+		defer close(stopped)
+		Iterate(0, func(v int) int { return v + 1 })(yield)
+	}, stopped
+}
+
+// slowEndless is like endless but sleeps for interval before each item.
+func slowEndless(interval time.Duration) (StreamX[int], <-chan struct{}) {
+	source, sourceStopped := endless()
+	return func(yield func(int) bool) {
+		source(func(v int) bool {
+			time.Sleep(interval)
+			return yield(v)
+		})
+	}, sourceStopped
+}
+
+// takeEach concurrently takes the first n items of every output.
+func takeEach(outputs []StreamX[int], n int) [][]int {
+	results := make([][]int, len(outputs))
+	var wg sync.WaitGroup
+	for i, output := range outputs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = StreamToSlice(Take[int](n)(output))
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// waitStopped fails the test unless every channel is closed within a second.
+func waitStopped(t *testing.T, chans ...<-chan struct{}) {
+	t.Helper()
+	for _, ch := range chans {
+		select {
+		case <-ch:
+		case <-time.After(time.Second):
+			t.Fatal("source was not stopped")
+		}
+	}
+}
+
+func TestPipelineWithoutMappersIsIdentity(t *testing.T) {
+	if !StreamEqual(Pipeline[int]()(Of(1, 2, 3)), Of(1, 2, 3)) {
+		t.Fatal("Pipeline[int]() changed the stream")
+	}
+}
+
+func TestCombineWithoutMappersIsIdentity(t *testing.T) {
+	if !StreamEqual(Combine[int]()(Of(1, 2, 3)), Of(1, 2, 3)) {
+		t.Fatal("Combine[int]() changed the stream")
+	}
+}
+
+func TestCombineAppliesMappersInOrder(t *testing.T) {
+	combined := Combine(
+		Map(func(v int) int { return v + 1 }),
+		Filter(func(v int) bool { return v%2 == 0 }),
+	)
+	if !StreamEqual(combined(Of(0, 1, 2, 3, 4)), Of(2, 4)) {
+		t.Fatal("Combine did not apply mappers left to right")
+	}
+}
+
+func TestCacheNestedIteration(t *testing.T) {
+	c := Cache(Of(1, 2))
+	count := 0
+	finishes(t, func() {
+		for range c {
+			for range c {
+				count++
+			}
+		}
+	})
+	if count != 4 {
+		t.Fatalf("got %d inner items, want 4", count)
+	}
+	if !StreamEqual(c, Of(1, 2)) {
+		t.Fatal("cache does not replay the source")
+	}
+}
+
+func TestCacheZipWithItself(t *testing.T) {
+	c := Cache(Of(1, 2, 3))
+	var got []Pair[int, int]
+	finishes(t, func() {
+		got = StreamToSlice(Zip(c, c))
+	})
+	if len(got) != 3 || got[2] != (Pair[int, int]{First: 3, Second: 3}) {
+		t.Fatalf("got %v", got)
+	}
+}
+
+func TestCacheIterationDuringPull(t *testing.T) {
+	c := Cache(Of(1, 2))
+	next, stop := Pull(c)
+	defer stop()
+	finishes(t, func() {
+		next()
+		StreamToSlice(c)
+	})
+}
+
+func TestCacheTTLServesStaleSnapshotDuringRefresh(t *testing.T) {
+	calls := 0
+	c := CacheTTL(StreamX[int](func(yield func(int) bool) {
+		calls++
+		for i := range 3 {
+			if !yield(calls*10 + i) {
+				return
+			}
+		}
+	}), 10*time.Millisecond)
+	StreamToSlice(c)
+	time.Sleep(20 * time.Millisecond)
+
+	finishes(t, func() {
+		for range c {
+			if !StreamEqual(c, Of(10, 11, 12)) {
+				t.Error("nested iteration did not replay the stale snapshot")
+			}
+		}
+	})
+	if !StreamEqual(c, Of(20, 21, 22)) {
+		t.Fatal("refresh was not cached")
+	}
+}
+
+func TestStreamToChannelContextStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	source := StreamX[int](func(yield func(int) bool) {
+		defer close(stopped)
+		for i := 0; yield(i); i++ {
+		}
+	})
+
+	ch := StreamToChannelContext(ctx, source, 0)
+	<-ch
+	cancel()
+	finishes(t, func() {
+		<-stopped
+		for range ch {
+			// Drain whatever was sent before the cancel
+		}
+	})
+}
+
+func TestStreamToChannelDeliversAll(t *testing.T) {
+	if !StreamEqual(ChannelToStream(StreamToChannel(Range(0, 100, 1), 4)), Range(0, 100, 1)) {
+		t.Fatal("StreamToChannel lost or reordered items")
+	}
+}
+
+func TestMergeStopsSourcesEarly(t *testing.T) {
+	a, aStopped := endless()
+	b, bStopped := endless()
+	noLeaks(t, func() {
+		if got := StreamToSlice(Take[int](5)(Merge(a, b))); len(got) != 5 {
+			t.Errorf("got %v", got)
+		}
+	})
+	waitStopped(t, aStopped, bStopped)
+}
+
+func TestMapParallelStopsSourceEarly(t *testing.T) {
+	source, sourceStopped := endless()
+	double := MapParallel(4, func(v int) int { return v * 2 })
+	noLeaks(t, func() {
+		if !StreamEqual(Take[int](5)(double(source)), Of(0, 2, 4, 6, 8)) {
+			t.Error("MapParallel did not keep input order")
+		}
+	})
+	waitStopped(t, sourceStopped)
+}
+
+func TestMapParallelUnorderedStopsSourceEarly(t *testing.T) {
+	source, sourceStopped := endless()
+	double := MapParallelUnordered(4, func(v int) int { return v * 2 })
+	noLeaks(t, func() {
+		if got := StreamToSlice(Take[int](5)(double(source))); len(got) != 5 {
+			t.Errorf("got %v", got)
+		}
+	})
+	waitStopped(t, sourceStopped)
+}
+
+func TestDebounceStopsSourceEarly(t *testing.T) {
+	source, sourceStopped := slowEndless(5 * time.Millisecond)
+	noLeaks(t, func() {
+		if got := StreamToSlice(Take[int](3)(Debounce[int](time.Millisecond)(source))); len(got) != 3 {
+			t.Errorf("got %v", got)
+		}
+	})
+	waitStopped(t, sourceStopped)
+}
+
+func TestBatchTimeoutStopsSourceEarly(t *testing.T) {
+	source, sourceStopped := endless()
+	noLeaks(t, func() {
+		got := StreamToSlice(Take[[]int](3)(BatchTimeout[int](2, time.Second)(source)))
+		if len(got) != 3 || len(got[2]) != 2 {
+			t.Errorf("got %v", got)
+		}
+	})
+	waitStopped(t, sourceStopped)
+}
+
+func TestBufferStopsSourceEarly(t *testing.T) {
+	source, sourceStopped := endless()
+	noLeaks(t, func() {
+		if !StreamEqual(Take[int](5)(Buffer[int](8)(source)), Of(0, 1, 2, 3, 4)) {
+			t.Error("Buffer did not keep input order")
+		}
+	})
+	waitStopped(t, sourceStopped)
+}
+
+func TestTeeStopsSourceWhenEveryOutputStops(t *testing.T) {
+	source, sourceStopped := endless()
+	noLeaks(t, func() {
+		outputs := Tee(source, 3)
+		results := make(chan []int, len(outputs))
+		for _, output := range outputs {
+			go func() {
+				results <- StreamToSlice(Take[int](3)(output))
+			}()
+		}
+		for range outputs {
+			if got := <-results; !StreamEqual(Of(got...), Of(0, 1, 2)) {
+				t.Errorf("got %v", got)
+			}
+		}
+	})
+	waitStopped(t, sourceStopped)
+}
+
+func TestBroadcastStopsSourceWhenEveryConsumerReturns(t *testing.T) {
+	source, sourceStopped := endless()
+	counts := make([]int, 3)
+	consumer := func(i int) func(StreamX[int]) {
+		return func(stream StreamX[int]) {
+			counts[i] = Count(Take[int](i + 1)(stream))
+		}
+	}
+	noLeaks(t, func() {
+		Broadcast(source, consumer(0), consumer(1), consumer(2))
+	})
+	waitStopped(t, sourceStopped)
+	for i, n := range counts {
+		if n != i+1 {
+			t.Errorf("consumer %d got %d items", i, n)
+		}
+	}
+}
+
+func TestWindowByTimeStopsSourceEarly(t *testing.T) {
+	source, sourceStopped := slowEndless(time.Millisecond)
+	noLeaks(t, func() {
+		if got := StreamToSlice(Take[[]int](2)(WindowByTime[int](10*time.Millisecond, false)(source))); len(got) != 2 {
+			t.Errorf("got %v", got)
+		}
+	})
+	waitStopped(t, sourceStopped)
+}
+
+func TestFanOutProcessesEveryItem(t *testing.T) {
+	var sum atomic.Int64
+	noLeaks(t, func() {
+		FanOut(4, func(v int) { sum.Add(int64(v)) })(Range(0, 100, 1))
+	})
+	if sum.Load() != 4950 {
+		t.Fatalf("got sum %d, want 4950", sum.Load())
+	}
+}
+
+func TestFanOutStopsSourceOnWorkerPanic(t *testing.T) {
+	source, sourceStopped := endless()
+	noLeaks(t, func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("worker panic was not re-raised")
+			}
+		}()
+		FanOut(4, func(v int) {
+			if v == 10 {
+				panic("boom")
+			}
+		})(source)
+	})
+	waitStopped(t, sourceStopped)
+}
+
+func TestPartitionStreamStopsSourceWhenBothStop(t *testing.T) {
+	source, sourceStopped := endless()
+	var got [][]int
+	noLeaks(t, func() {
+		even, odd := PartitionStream(func(v int) bool { return v%2 == 0 })(source)
+		got = takeEach([]StreamX[int]{even, odd}, 3)
+	})
+	waitStopped(t, sourceStopped)
+	if fmt.Sprint(got) != "[[0 2 4] [1 3 5]]" {
+		t.Fatalf("got %v", got)
+	}
+}
+
+func TestRoundRobinStopsSourceWhenEveryOutputStops(t *testing.T) {
+	source, sourceStopped := endless()
+	var got [][]int
+	noLeaks(t, func() {
+		got = takeEach(RoundRobin(source, 3), 2)
+	})
+	waitStopped(t, sourceStopped)
+	if fmt.Sprint(got) != "[[0 3] [1 4] [2 5]]" {
+		t.Fatalf("got %v", got)
+	}
+}
+
+func TestMergeChannelsStopsReadersEarly(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	a, aStopped := endless()
+	b, bStopped := endless()
+	noLeaks(t, func() {
+		chans := make(chan (<-chan int), 2)
+		chans <- StreamToChannelContext(ctx, a, 0)
+		chans <- StreamToChannelContext(ctx, b, 0)
+		if got := StreamToSlice(Take[int](5)(MergeChannels(chans))); len(got) != 5 {
+			t.Errorf("got %v", got)
+		}
+		cancel()
+	})
+	waitStopped(t, aStopped, bStopped)
+}
+
+func TestMergeChannelsEndsWhenAllChannelsClose(t *testing.T) {
+	chans := make(chan (<-chan int), 3)
+	for i := range 3 {
+		chans <- StreamToChannel(Range(i*10, i*10+10, 1), 0)
+	}
+	close(chans)
+	var got int
+	noLeaks(t, func() {
+		got = Count(MergeChannels(chans))
+	})
+	if got != 30 {
+		t.Fatalf("got %d items, want 30", got)
+	}
+}
+
+func TestGroupAdjacentCallsKeyOncePerItem(t *testing.T) {
+	calls := 0
+	key := func(s string) string {
+		calls++
+		return s
+	}
+	got := StreamToSlice(GroupAdjacent(key)(Of("a", "a", "b", "a")))
+	if fmt.Sprint(got) != "[{a [a a]} {b [b]} {a [a]}]" {
+		t.Fatalf("got %v", got)
+	}
+	if calls != 4 {
+		t.Fatalf("key called %d times, want 4", calls)
+	}
+}
+
+func TestSplitOnMatchesStringsSplit(t *testing.T) {
+	cases := map[string]string{
+		"1,0":     "[[1] []]",
+		"0":       "[[] []]",
+		"1,0,0,2": "[[1] [] [2]]",
+		"1,2":     "[[1 2]]",
+	}
+	for input, want := range cases {
+		var items []int
+		for _, field := range strings.Split(input, ",") {
+			n, _ := strconv.Atoi(field)
+			items = append(items, n)
+		}
+		if got := fmt.Sprint(StreamToSlice(SplitOn(0)(Of(items...)))); got != want {
+			t.Errorf("SplitOn(%s) = %s, want %s", input, got, want)
+		}
+	}
+	if got := StreamToSlice(SplitOn(0)(Empty[int]())); len(got) != 0 {
+		t.Errorf("empty stream gave %v", got)
+	}
+}